@@ -16,10 +16,13 @@ package app
 
 import (
 	"context"
+	"fmt"
 	"io/fs"
 	"net/http"
 	"path"
+	"time"
 
+	"github.com/gin-contrib/cors"
 	"github.com/gin-contrib/gzip"
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-contrib/secure"
@@ -33,15 +36,27 @@ import (
 	"github.com/kubetail-org/kubetail/modules/shared/k8shelpers"
 	"github.com/kubetail-org/kubetail/modules/shared/middleware"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
 	clusterapi "github.com/kubetail-org/kubetail/modules/cluster-api"
 	"github.com/kubetail-org/kubetail/modules/cluster-api/graph"
+	"github.com/kubetail-org/kubetail/modules/cluster-api/internal/apq"
+	"github.com/kubetail-org/kubetail/modules/cluster-api/internal/audit"
+	"github.com/kubetail-org/kubetail/modules/cluster-api/internal/auth"
+	clustercors "github.com/kubetail-org/kubetail/modules/cluster-api/internal/cors"
+	"github.com/kubetail-org/kubetail/modules/cluster-api/internal/metrics"
+	"github.com/kubetail-org/kubetail/modules/cluster-api/internal/probes"
 )
 
 type App struct {
 	*gin.Engine
-	cm             k8shelpers.ConnectionManager
-	grpcDispatcher *grpcdispatcher.Dispatcher
-	graphqlServer  *graph.Server
+	cm               k8shelpers.ConnectionManager
+	grpcDispatcher   *grpcdispatcher.Dispatcher
+	graphqlServer    *graph.Server
+	authProvider     auth.Provider
+	metricsServer    *http.Server
+	metricsWatchStop chan struct{}
+	auditor          *audit.Auditor
 
 	// for testing
 	dynamicRoutes *gin.RouterGroup
@@ -49,6 +64,11 @@ type App struct {
 
 // Shutdown
 func (a *App) Shutdown(ctx context.Context) error {
+	// Stop the gRPC dispatcher metrics watcher
+	if a.metricsWatchStop != nil {
+		close(a.metricsWatchStop)
+	}
+
 	// Stop grpc dispatcher
 	if a.grpcDispatcher != nil {
 		// TODO: log dispatcher shutdown errors
@@ -58,6 +78,19 @@ func (a *App) Shutdown(ctx context.Context) error {
 	// Shutdown GraphQL server
 	a.graphqlServer.Shutdown()
 
+	// Stop and flush the audit sinks
+	if a.auditor != nil {
+		// TODO: log audit shutdown errors
+		a.auditor.Close()
+	}
+
+	// Shutdown metrics listener, if running on its own port
+	if a.metricsServer != nil {
+		if err := a.metricsServer.Shutdown(ctx); err != nil {
+			return err
+		}
+	}
+
 	// Shutdown connection manager
 	return a.cm.Shutdown(ctx)
 }
@@ -67,6 +100,19 @@ func NewApp(cfg *config.Config) (*App, error) {
 	// Init app
 	app := &App{Engine: gin.New()}
 
+	// Init auth provider (falls back to single-tenant authenticationMiddleware when disabled)
+	authProvider, err := auth.NewProvider(cfg)
+	if err != nil {
+		return nil, err
+	}
+	app.authProvider = authProvider
+
+	// Init metrics registry
+	var metricsRegistry *metrics.Registry
+	if cfg.ClusterAPI.Metrics.Enabled {
+		metricsRegistry = metrics.NewRegistry()
+	}
+
 	// If not in test-mode
 	if gin.Mode() != gin.TestMode {
 		app.Use(gin.Recovery())
@@ -80,6 +126,12 @@ func NewApp(cfg *config.Config) (*App, error) {
 
 		// init grpc dispatcher
 		app.grpcDispatcher = mustNewGrpcDispatcher(cfg)
+
+		// Keep the dispatcher connection gauges current
+		if metricsRegistry != nil {
+			app.metricsWatchStop = make(chan struct{})
+			metricsRegistry.WatchGRPCDispatcher(app.grpcDispatcher, 15*time.Second, app.metricsWatchStop)
+		}
 	}
 
 	// Add request-id middleware
@@ -93,6 +145,11 @@ func NewApp(cfg *config.Config) (*App, error) {
 	// Gzip middleware
 	app.Use(gzip.Gzip(gzip.DefaultCompression))
 
+	// Metrics middleware
+	if metricsRegistry != nil {
+		app.Use(metricsRegistry.Middleware())
+	}
+
 	// Routes
 	root := app.Group(cfg.ClusterAPI.BasePath)
 
@@ -108,6 +165,16 @@ func NewApp(cfg *config.Config) (*App, error) {
 			ContentTypeNosniff:    true,
 		}))
 
+		// CORS middleware (registered before CSRF so that preflight OPTIONS
+		// requests, which gin-contrib/cors answers directly, never reach it)
+		if cfg.ClusterAPI.CORS.Enabled {
+			corsConfig, err := clustercors.BuildConfig(cfg.ClusterAPI.CORS)
+			if err != nil {
+				return nil, err
+			}
+			dynamicRoutes.Use(cors.New(corsConfig))
+		}
+
 		// Disable csrf protection for graphql endpoint (already rejects simple requests)
 		dynamicRoutes.Use(func(c *gin.Context) {
 			if c.Request.URL.Path == path.Join(cfg.ClusterAPI.BasePath, "/graphql") {
@@ -142,11 +209,53 @@ func NewApp(cfg *config.Config) (*App, error) {
 		}
 
 		// authentication middleware
-		dynamicRoutes.Use(authenticationMiddleware)
+		if app.authProvider != nil {
+			dynamicRoutes.Use(app.authProvider.Middleware())
+		} else {
+			dynamicRoutes.Use(authenticationMiddleware)
+		}
+
+		// When auth is enabled, build per-identity Kubernetes clients via
+		// impersonation so resolvers scope access to the authenticated
+		// caller instead of the shared connection manager.
+		var authClientFactory *auth.ClientFactory
+		if app.authProvider != nil {
+			authClientFactory, err = auth.NewClientFactory()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Track active GraphQL subscriptions
+		if metricsRegistry != nil {
+			dynamicRoutes.Use(metricsRegistry.GraphQLSubscriptionMiddleware())
+		}
 
 		// GraphQL endpoint
-		app.graphqlServer = graph.NewServer(app.cm, app.grpcDispatcher, cfg.AllowedNamespaces, csrfProtect)
-		dynamicRoutes.Any("/graphql", gin.WrapH(app.graphqlServer))
+		app.graphqlServer = graph.NewServer(app.cm, authClientFactory, app.grpcDispatcher, cfg.AllowedNamespaces, csrfProtect)
+
+		var graphqlHandler http.Handler = app.graphqlServer
+
+		// Audit logging (innermost, so it sees the final resolved operation)
+		if cfg.ClusterAPI.Audit.Enabled {
+			auditor, err := audit.NewAuditorFromConfig(cfg.ClusterAPI.Audit)
+			if err != nil {
+				return nil, err
+			}
+			app.auditor = auditor // so Shutdown can stop/flush its sinks
+			graphqlHandler = audit.Interceptor(auditor, graphqlHandler)
+		}
+
+		// Automatic Persisted Queries
+		if cfg.ClusterAPI.GraphQL.PersistedQueries.Enabled {
+			apqCache, err := apq.NewCache(cfg.ClusterAPI.GraphQL.PersistedQueries)
+			if err != nil {
+				return nil, err
+			}
+			graphqlHandler = apq.Middleware(apqCache, graphqlHandler)
+		}
+
+		dynamicRoutes.Any("/graphql", gin.WrapH(graphqlHandler))
 	}
 	app.dynamicRoutes = dynamicRoutes // for unit tests
 
@@ -155,13 +264,43 @@ func NewApp(cfg *config.Config) (*App, error) {
 		c.String(http.StatusOK, "Kubetail Cluster API")
 	})
 
-	// Health endpoint
+	// Health endpoint (kept for backwards compatibility; see /livez and /readyz)
 	root.GET("/healthz", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"status": "ok",
 		})
 	})
 
+	// Liveness endpoint
+	root.GET("/livez", probes.LivezHandler())
+
+	// Readiness endpoint
+	root.GET("/readyz", probes.ReadyzHandler(app.readyzChecks()))
+
+	// Metrics endpoint
+	if metricsRegistry != nil {
+		metricsHandler := gin.WrapH(promhttp.HandlerFor(metricsRegistry.Gatherer(), promhttp.HandlerOpts{}))
+
+		if cfg.ClusterAPI.Metrics.BindAddress == "" {
+			// Same-mux: expose alongside the rest of the API
+			root.GET("/metrics", metricsHandler)
+		} else {
+			// Separate listener, e.g. so it can stay off a public-facing mux
+			metricsMux := http.NewServeMux()
+			metricsMux.Handle("/metrics", promhttp.HandlerFor(metricsRegistry.Gatherer(), promhttp.HandlerOpts{}))
+
+			app.metricsServer = &http.Server{
+				Addr:    cfg.ClusterAPI.Metrics.BindAddress,
+				Handler: metricsMux,
+			}
+
+			go func() {
+				// TODO: log metrics listener errors
+				_ = app.metricsServer.ListenAndServe()
+			}()
+		}
+	}
+
 	// Init staticFS
 	sub, err := fs.Sub(clusterapi.StaticEmbedFS, "static")
 	if err != nil {
@@ -177,3 +316,41 @@ func NewApp(cfg *config.Config) (*App, error) {
 
 	return app, nil
 }
+
+// readyzChecks returns the dependency checks run by /readyz: a Kubernetes
+// API connectivity check via the connection manager's cached server
+// version, and a gRPC dispatcher check that fails when no node agents are
+// connected or when any have failed to connect.
+func (a *App) readyzChecks() []probes.Check {
+	var checks []probes.Check
+
+	if a.cm != nil {
+		checks = append(checks, probes.Check{
+			Name: "kubernetes-api",
+			Func: func(ctx context.Context) error {
+				if _, err := a.cm.ServerVersion(ctx); err != nil {
+					return fmt.Errorf("unable to reach kubernetes api: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+
+	if a.grpcDispatcher != nil {
+		checks = append(checks, probes.Check{
+			Name: "grpc-dispatcher",
+			Func: func(ctx context.Context) error {
+				connected, failed := a.grpcDispatcher.ConnectedCount(), a.grpcDispatcher.FailedCount()
+				if connected == 0 {
+					return fmt.Errorf("no node agents connected")
+				}
+				if failed > 0 {
+					return fmt.Errorf("%d node agent(s) failed to connect", failed)
+				}
+				return nil
+			},
+		})
+	}
+
+	return checks
+}
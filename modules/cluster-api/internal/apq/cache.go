@@ -0,0 +1,166 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apq implements Apollo Automatic Persisted Queries for the
+// cluster-api GraphQL endpoint: clients send a query's sha256 hash
+// instead of its full text, registering the text on a cache miss so
+// that subsequent requests (and CDN/proxy caches) only need the hash.
+package apq
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+)
+
+// Cache maps a persisted query's sha256 hash to its query text.
+type Cache interface {
+	// Get returns the query text registered for hash, if any.
+	Get(ctx context.Context, hash string) (string, bool)
+
+	// Put registers query under hash, returning false if hash was rejected
+	// (e.g. outside a configured allow-list) rather than registered. The
+	// caller must not execute query when Put returns false.
+	Put(ctx context.Context, hash string, query string) bool
+}
+
+// NewCache constructs the Cache configured via
+// cfg.ClusterAPI.GraphQL.PersistedQueries. When cfg.AllowList is non-empty,
+// the returned Cache is locked down to exactly those hashes as defense in
+// depth: a client can still resolve and register one of the allow-listed
+// hashes the first time it sends the full query, but a hash outside the
+// list is rejected outright rather than silently accepted on a cache miss.
+func NewCache(cfg config.PersistedQueriesConfig) (Cache, error) {
+	var (
+		cache Cache
+		err   error
+	)
+
+	if cfg.Redis.Enabled {
+		cache, err = newRedisCache(cfg.Redis)
+	} else {
+		size := cfg.CacheSize
+		if size <= 0 {
+			size = 1000
+		}
+		cache, err = newLRUCache(size)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.AllowList) == 0 {
+		return cache, nil
+	}
+
+	return newAllowListCache(cache, cfg.AllowList), nil
+}
+
+// allowListCache wraps a Cache so that only hashes an operator has
+// pre-approved can ever resolve, preventing a client from using APQ's
+// register-on-miss behavior to smuggle arbitrary queries onto the server.
+type allowListCache struct {
+	inner   Cache
+	allowed map[string]struct{}
+}
+
+func newAllowListCache(inner Cache, hashes []string) *allowListCache {
+	allowed := make(map[string]struct{}, len(hashes))
+	for _, hash := range hashes {
+		allowed[hash] = struct{}{}
+	}
+	return &allowListCache{inner: inner, allowed: allowed}
+}
+
+func (c *allowListCache) Get(ctx context.Context, hash string) (string, bool) {
+	if _, ok := c.allowed[hash]; !ok {
+		return "", false
+	}
+	return c.inner.Get(ctx, hash)
+}
+
+func (c *allowListCache) Put(ctx context.Context, hash string, query string) bool {
+	if _, ok := c.allowed[hash]; !ok {
+		return false
+	}
+	return c.inner.Put(ctx, hash, query)
+}
+
+// lruCache is the default, in-process Cache backend.
+type lruCache struct {
+	cache *lru.Cache[string, string]
+}
+
+func newLRUCache(size int) (*lruCache, error) {
+	cache, err := lru.New[string, string](size)
+	if err != nil {
+		return nil, fmt.Errorf("apq: failed to create lru cache: %w", err)
+	}
+	return &lruCache{cache: cache}, nil
+}
+
+func (c *lruCache) Get(_ context.Context, hash string) (string, bool) {
+	return c.cache.Get(hash)
+}
+
+func (c *lruCache) Put(_ context.Context, hash string, query string) bool {
+	c.cache.Add(hash, query)
+	return true
+}
+
+// redisCache is an optional shared Cache backend for multi-replica
+// deployments, so that a persisted query registered against one replica
+// is visible to requests routed to another.
+type redisCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func newRedisCache(cfg config.RedisConfig) (*redisCache, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.Address,
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+
+	ttl := cfg.TTL
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+
+	return &redisCache{client: client, ttl: ttl}, nil
+}
+
+func (c *redisCache) Get(ctx context.Context, hash string) (string, bool) {
+	query, err := c.client.Get(ctx, redisKey(hash)).Result()
+	if err != nil {
+		return "", false
+	}
+	return query, true
+}
+
+func (c *redisCache) Put(ctx context.Context, hash string, query string) bool {
+	// TODO: log redis errors once a logger is threaded through
+	_ = c.client.Set(ctx, redisKey(hash), query, c.ttl).Err()
+	return true
+}
+
+func redisKey(hash string) string {
+	return "kubetail:apq:" + hash
+}
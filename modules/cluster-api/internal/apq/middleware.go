@@ -0,0 +1,176 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apq
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// persistedQueryExtension is the shape of the `extensions.persistedQuery`
+// field Apollo Client sends, per the APQ spec.
+type persistedQueryExtension struct {
+	Version    int    `json:"version"`
+	Sha256Hash string `json:"sha256Hash"`
+}
+
+type requestExtensions struct {
+	PersistedQuery *persistedQueryExtension `json:"persistedQuery"`
+}
+
+// requestBody is the subset of a GraphQL-over-HTTP request this
+// middleware needs to read and rewrite.
+type requestBody struct {
+	Query         string            `json:"query"`
+	OperationName string            `json:"operationName,omitempty"`
+	Variables     json.RawMessage   `json:"variables,omitempty"`
+	Extensions    requestExtensions `json:"extensions"`
+}
+
+// errorBody matches the shape graphql-go/graphql-transport-ws clients
+// expect for a top-level GraphQL error.
+type errorBody struct {
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func persistedQueryNotFound() errorBody {
+	var body errorBody
+	body.Errors = append(body.Errors, struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	}{
+		Message:    "PersistedQueryNotFound",
+		Extensions: map[string]any{"code": "PERSISTED_QUERY_NOT_FOUND"},
+	})
+	return body
+}
+
+func persistedQueryNotAllowed() errorBody {
+	var body errorBody
+	body.Errors = append(body.Errors, struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	}{
+		Message:    "PersistedQueryNotAllowed",
+		Extensions: map[string]any{"code": "PERSISTED_QUERY_NOT_ALLOWED"},
+	})
+	return body
+}
+
+// Middleware returns an http.Handler middleware that implements Apollo
+// Automatic Persisted Queries in front of next: it resolves a request's
+// `extensions.persistedQuery` hash against cache, responding with
+// PersistedQueryNotFound when the hash is unknown so the client can
+// retry with the full query text (which this middleware then registers).
+func Middleware(cache Cache, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body requestBody
+		var rewriteGET bool
+
+		switch r.Method {
+		case http.MethodPost:
+			raw, err := io.ReadAll(r.Body)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			r.Body.Close()
+			r.Body = io.NopCloser(bytes.NewReader(raw))
+
+			if err := json.Unmarshal(raw, &body); err != nil {
+				// Not JSON (or not an APQ request); pass through unmodified.
+				next.ServeHTTP(w, r)
+				return
+			}
+		case http.MethodGet:
+			if ext := r.URL.Query().Get("extensions"); ext != "" {
+				var parsed requestExtensions
+				if err := json.Unmarshal([]byte(ext), &parsed); err == nil {
+					body.Extensions = parsed
+				}
+			}
+			body.Query = r.URL.Query().Get("query")
+			rewriteGET = true
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		pq := body.Extensions.PersistedQuery
+		if pq == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+
+		if body.Query == "" {
+			// Hash-only request: resolve it from cache or report the miss.
+			query, ok := cache.Get(ctx, pq.Sha256Hash)
+			if !ok {
+				writeJSON(w, http.StatusOK, persistedQueryNotFound())
+				return
+			}
+			body.Query = query
+		} else {
+			// Full query + hash: verify and register it for future hash-only requests.
+			if sha256Hex(body.Query) != pq.Sha256Hash {
+				http.Error(w, "provided sha256Hash does not match query", http.StatusBadRequest)
+				return
+			}
+			if !cache.Put(ctx, pq.Sha256Hash, body.Query) {
+				// Put refused the hash (e.g. it's outside a configured
+				// allow-list); the request must not reach the GraphQL
+				// server regardless of how the hash was verified above.
+				writeJSON(w, http.StatusForbidden, persistedQueryNotAllowed())
+				return
+			}
+		}
+
+		if rewriteGET {
+			q := r.URL.Query()
+			q.Set("query", body.Query)
+			r.URL.RawQuery = q.Encode()
+		} else {
+			raw, err := json.Marshal(body)
+			if err != nil {
+				http.Error(w, "failed to rebuild request body", http.StatusInternalServerError)
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(raw))
+			r.ContentLength = int64(len(raw))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
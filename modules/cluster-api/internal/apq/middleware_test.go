@@ -0,0 +1,114 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apq
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddlewarePreservesOperationNameOnRegister(t *testing.T) {
+	cache, err := newLRUCache(10)
+	if err != nil {
+		t.Fatalf("newLRUCache: %v", err)
+	}
+
+	var gotBody requestBody
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(raw, &gotBody)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler := Middleware(cache, next)
+
+	query := "query Logs { logs }"
+	hash := sha256Hex(query)
+	reqBody := `{"operationName":"Logs","query":"` + query + `","extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + hash + `"}}}`
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(reqBody))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if gotBody.OperationName != "Logs" {
+		t.Errorf("operationName = %q, want %q (dropped during APQ re-marshal)", gotBody.OperationName, "Logs")
+	}
+}
+
+func TestAllowListCacheRejectsUnapprovedHash(t *testing.T) {
+	inner, err := newLRUCache(10)
+	if err != nil {
+		t.Fatalf("newLRUCache: %v", err)
+	}
+
+	approved := sha256Hex("query Approved { ok }")
+	cache := newAllowListCache(inner, []string{approved})
+
+	if !cache.Put(nil, approved, "query Approved { ok }") {
+		t.Error("Put(approved) = false, want true")
+	}
+	if _, ok := cache.Get(nil, approved); !ok {
+		t.Error("Get(approved) = false, want true")
+	}
+
+	unapproved := sha256Hex("query Unapproved { ok }")
+	if cache.Put(nil, unapproved, "query Unapproved { ok }") {
+		t.Error("Put(unapproved) = true, want false (hash outside the allow-list must be rejected, not just uncached)")
+	}
+	if _, ok := cache.Get(nil, unapproved); ok {
+		t.Error("Get(unapproved) = true, want false (hash outside the allow-list must never resolve)")
+	}
+}
+
+// TestMiddlewareRejectsFullQueryOutsideAllowList proves the allow-list is
+// enforced on the actual request path, not just against the cache in
+// isolation: a client that sends the full query text alongside its
+// matching (but unapproved) hash must not reach next.
+func TestMiddlewareRejectsFullQueryOutsideAllowList(t *testing.T) {
+	inner, err := newLRUCache(10)
+	if err != nil {
+		t.Fatalf("newLRUCache: %v", err)
+	}
+	cache := newAllowListCache(inner, nil) // nothing is approved
+
+	nextCalled := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nextCalled = true
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler := Middleware(cache, next)
+
+	query := "query Sneaky { secrets }"
+	hash := sha256Hex(query)
+	reqBody := `{"query":"` + query + `","extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + hash + `"}}}`
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(reqBody))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	if nextCalled {
+		t.Fatal("next was called for a query whose hash is outside the allow-list")
+	}
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
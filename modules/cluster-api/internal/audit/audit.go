@@ -0,0 +1,104 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package audit captures authenticated GraphQL requests as structured
+// events and dispatches them to pluggable sinks, mirroring kube-apiserver
+// audit policy semantics closely enough that operators can reuse the
+// concepts (level, stages-free single event per request, redaction).
+package audit
+
+import (
+	"io"
+	"time"
+)
+
+// Level controls how much detail an Event carries, mirroring
+// kube-apiserver's audit levels.
+type Level string
+
+const (
+	// LevelMetadata records everything except the query and variables.
+	LevelMetadata Level = "Metadata"
+
+	// LevelRequest additionally records the query and redacted variables.
+	LevelRequest Level = "Request"
+)
+
+// Event is a single audited GraphQL request.
+type Event struct {
+	Timestamp     time.Time      `json:"timestamp"`
+	OperationName string         `json:"operationName,omitempty"`
+	Query         string         `json:"query,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	Username      string         `json:"username,omitempty"`
+	Groups        []string       `json:"groups,omitempty"`
+	Namespaces    []string       `json:"namespaces,omitempty"`
+	Duration      time.Duration  `json:"duration"`
+	StatusCode    int            `json:"statusCode"`
+	Error         string         `json:"error,omitempty"`
+	RequestID     string         `json:"requestId,omitempty"`
+}
+
+// Sink receives audit events. Implementations must be safe to call
+// concurrently.
+type Sink interface {
+	Write(event Event) error
+}
+
+// Auditor records GraphQL requests and fans them out to Sinks.
+type Auditor struct {
+	level    Level
+	sinks    []Sink
+	redactor *Redactor
+}
+
+// NewAuditor creates an Auditor that dispatches to sinks.
+func NewAuditor(level Level, redactor *Redactor, sinks ...Sink) *Auditor {
+	return &Auditor{level: level, redactor: redactor, sinks: sinks}
+}
+
+// Record applies the configured level and redaction to event and writes
+// it to every sink. Sink errors don't
+// block each other or the caller; callers that care about delivery should
+// check a sink's own metrics/logs.
+func (a *Auditor) Record(event Event) {
+	if a.level == LevelMetadata {
+		event.Query = ""
+		event.Variables = nil
+	} else if a.redactor != nil {
+		event.Variables = a.redactor.Redact(event.Variables)
+	}
+
+	for _, sink := range a.sinks {
+		// TODO: log sink write errors once a logger is threaded through
+		_ = sink.Write(event)
+	}
+}
+
+// Close stops any sinks that own background resources (e.g. the webhook
+// sink's batch-flush goroutine), flushing pending events first. Callers
+// should invoke this during graceful shutdown.
+func (a *Auditor) Close() error {
+	var firstErr error
+	for _, sink := range a.sinks {
+		closer, ok := sink.(io.Closer)
+		if !ok {
+			continue
+		}
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
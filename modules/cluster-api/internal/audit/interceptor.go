@@ -0,0 +1,195 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/kubetail-org/kubetail/modules/cluster-api/internal/auth"
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+)
+
+// requestIDHeader matches the header gin-contrib/requestid sets on the
+// response, which runs ahead of this interceptor in the middleware chain.
+const requestIDHeader = "X-Request-Id"
+
+type graphqlRequestBody struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// parseGraphQLRequest extracts the query, operation name and variables
+// from a GraphQL-over-HTTP request, whether it arrives as a JSON POST
+// body or as GET query-string parameters (the shape Apollo Client - and
+// the apq.Middleware that rewrites persisted-query hashes into this form
+// - uses for cacheable, CDN-friendly requests).
+func parseGraphQLRequest(r *http.Request) graphqlRequestBody {
+	var body graphqlRequestBody
+
+	switch r.Method {
+	case http.MethodPost:
+		raw, err := io.ReadAll(r.Body)
+		if err != nil {
+			return body
+		}
+		r.Body = io.NopCloser(bytes.NewReader(raw))
+		_ = json.Unmarshal(raw, &body)
+	case http.MethodGet:
+		q := r.URL.Query()
+		body.Query = q.Get("query")
+		body.OperationName = q.Get("operationName")
+		if rawVariables := q.Get("variables"); rawVariables != "" {
+			_ = json.Unmarshal([]byte(rawVariables), &body.Variables)
+		}
+	}
+
+	return body
+}
+
+// responseRecorder captures the status code and a best-effort GraphQL
+// error message from the wrapped handler's response.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// Interceptor wraps next (the GraphQL handler) and records an Event per
+// request to auditor, for both POST (JSON body) and GET (query-string)
+// GraphQL requests.
+func Interceptor(auditor *Auditor, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqBody := parseGraphQLRequest(r)
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(recorder, r)
+		duration := time.Since(start)
+
+		identity, _ := auth.IdentityFromContext(r.Context())
+
+		event := Event{
+			Timestamp:     time.Now(),
+			OperationName: reqBody.OperationName,
+			Query:         reqBody.Query,
+			Variables:     reqBody.Variables,
+			Username:      identity.Username,
+			Groups:        identity.Groups,
+			Namespaces:    namespacesFromVariables(reqBody.Variables),
+			Duration:      duration,
+			StatusCode:    recorder.status,
+			RequestID:     recorder.Header().Get(requestIDHeader),
+		}
+
+		if errMsg := graphqlErrorFrom(recorder.body.Bytes()); errMsg != "" {
+			event.Error = errMsg
+		}
+
+		auditor.Record(event)
+	})
+}
+
+// namespacesFromVariables does a best-effort scan of common variable
+// names used across the cluster-api's resolvers to scope a query to one
+// or more namespaces.
+func namespacesFromVariables(variables map[string]any) []string {
+	if variables == nil {
+		return nil
+	}
+
+	for _, key := range []string{"namespace", "namespaces"} {
+		switch v := variables[key].(type) {
+		case string:
+			if v != "" {
+				return []string{v}
+			}
+		case []any:
+			out := make([]string, 0, len(v))
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					out = append(out, s)
+				}
+			}
+			if len(out) > 0 {
+				return out
+			}
+		}
+	}
+
+	return nil
+}
+
+// graphqlErrorFrom returns the first error message in a GraphQL response
+// body, or "" if the response has no top-level "errors" array.
+func graphqlErrorFrom(body []byte) string {
+	var parsed struct {
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &parsed); err != nil || len(parsed.Errors) == 0 {
+		return ""
+	}
+	return parsed.Errors[0].Message
+}
+
+// NewAuditorFromConfig builds an Auditor from cfg.ClusterAPI.Audit,
+// wiring up whichever sinks are enabled.
+func NewAuditorFromConfig(cfg config.AuditConfig) (*Auditor, error) {
+	var sinks []Sink
+
+	if cfg.Sinks.Stdout.Enabled {
+		sinks = append(sinks, NewStdoutSink(os.Stdout))
+	}
+
+	if cfg.Sinks.File.Enabled {
+		sinks = append(sinks, NewFileSink(cfg.Sinks.File))
+	}
+
+	if cfg.Sinks.Webhook.Enabled {
+		if cfg.Sinks.Webhook.URL == "" {
+			return nil, fmt.Errorf("audit: webhook sink requires a url")
+		}
+		sinks = append(sinks, NewWebhookSink(cfg.Sinks.Webhook))
+	}
+
+	if len(sinks) == 0 {
+		return nil, fmt.Errorf("audit: enabled but no sinks are configured")
+	}
+
+	level := Level(cfg.Level)
+	if level == "" {
+		level = LevelRequest
+	}
+
+	return NewAuditor(level, NewRedactor(cfg.Redact.VariableNames), sinks...), nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// recordingSink captures every Event written to it for assertions.
+type recordingSink struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (s *recordingSink) Write(event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+func (s *recordingSink) last() (Event, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.events) == 0 {
+		return Event{}, false
+	}
+	return s.events[len(s.events)-1], true
+}
+
+func TestInterceptorCapturesGETRequests(t *testing.T) {
+	sink := &recordingSink{}
+	auditor := NewAuditor(LevelRequest, nil, sink)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler := Interceptor(auditor, next)
+
+	req := httptest.NewRequest(http.MethodGet, "/graphql?query=query+Logs%7Bid%7D&operationName=Logs&variables=%7B%22namespace%22%3A%22demo%22%7D", nil)
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	event, ok := sink.last()
+	if !ok {
+		t.Fatal("expected an audit event to be recorded for the GET request")
+	}
+	if event.OperationName != "Logs" {
+		t.Errorf("OperationName = %q, want %q", event.OperationName, "Logs")
+	}
+	if event.Query == "" {
+		t.Error("Query should be populated from the GET query string")
+	}
+	if len(event.Namespaces) != 1 || event.Namespaces[0] != "demo" {
+		t.Errorf("Namespaces = %v, want [demo]", event.Namespaces)
+	}
+}
+
+func TestInterceptorCapturesPOSTRequests(t *testing.T) {
+	sink := &recordingSink{}
+	auditor := NewAuditor(LevelRequest, nil, sink)
+
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	handler := Interceptor(auditor, next)
+
+	body := `{"operationName":"Logs","query":"query Logs { logs }","variables":{"namespace":"demo"}}`
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, req)
+
+	event, ok := sink.last()
+	if !ok {
+		t.Fatal("expected an audit event to be recorded for the POST request")
+	}
+	if event.OperationName != "Logs" {
+		t.Errorf("OperationName = %q, want %q", event.OperationName, "Logs")
+	}
+	if len(event.Namespaces) != 1 || event.Namespaces[0] != "demo" {
+		t.Errorf("Namespaces = %v, want [demo]", event.Namespaces)
+	}
+}
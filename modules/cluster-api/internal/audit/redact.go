@@ -0,0 +1,65 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+const redactedPlaceholder = "REDACTED"
+
+// Redactor masks configured variable names (e.g. "password", "token")
+// anywhere they appear in a GraphQL request's variables, including
+// nested objects.
+type Redactor struct {
+	keys map[string]struct{}
+}
+
+// NewRedactor creates a Redactor that masks the given variable names,
+// case-sensitively, wherever they appear in a variables map.
+func NewRedactor(keys []string) *Redactor {
+	set := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		set[k] = struct{}{}
+	}
+	return &Redactor{keys: set}
+}
+
+// Redact returns a copy of variables with configured keys masked.
+func (r *Redactor) Redact(variables map[string]any) map[string]any {
+	if variables == nil {
+		return nil
+	}
+	return r.redactValue(variables).(map[string]any)
+}
+
+func (r *Redactor) redactValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, nested := range val {
+			if _, redact := r.keys[k]; redact {
+				out[k] = redactedPlaceholder
+			} else {
+				out[k] = r.redactValue(nested)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = r.redactValue(item)
+		}
+		return out
+	default:
+		return val
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+)
+
+// stdoutSink writes one JSON object per line to an io.Writer, typically
+// os.Stdout so it's picked up by the container runtime's log collector.
+type stdoutSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewStdoutSink creates a Sink that writes newline-delimited JSON to w.
+func NewStdoutSink(w io.Writer) Sink {
+	return &stdoutSink{w: w}
+}
+
+func (s *stdoutSink) Write(event Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	enc := json.NewEncoder(s.w)
+	return enc.Encode(event)
+}
+
+// fileSink writes newline-delimited JSON to a rotating log file.
+type fileSink struct {
+	logger *lumberjack.Logger
+}
+
+// NewFileSink creates a Sink that writes newline-delimited JSON to cfg.Path,
+// rotating according to cfg's size/age/backup settings.
+func NewFileSink(cfg config.AuditFileSinkConfig) Sink {
+	return &fileSink{
+		logger: &lumberjack.Logger{
+			Filename:   cfg.Path,
+			MaxSize:    cfg.MaxSizeMB,
+			MaxAge:     cfg.MaxAgeDays,
+			MaxBackups: cfg.MaxBackups,
+			Compress:   cfg.Compress,
+		},
+	}
+}
+
+func (s *fileSink) Write(event Event) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	raw = append(raw, '\n')
+	_, err = s.logger.Write(raw)
+	return err
+}
+
+// webhookSink batches events and POSTs them to a webhook URL, in the
+// style of a Kubernetes audit-webhook backend.
+type webhookSink struct {
+	url        string
+	client     *http.Client
+	batchSize  int
+	flushEvery time.Duration
+
+	mu      sync.Mutex
+	pending []Event
+
+	done chan struct{}
+}
+
+// NewWebhookSink creates a Sink that batches events and flushes them to
+// cfg.URL either when batchSize events have accumulated or flushEvery has
+// elapsed, whichever comes first. Call Close to stop the background
+// flush loop and flush any remaining events.
+func NewWebhookSink(cfg config.AuditWebhookSinkConfig) *webhookSink {
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+
+	flushEvery := time.Duration(cfg.FlushIntervalSeconds) * time.Second
+	if flushEvery <= 0 {
+		flushEvery = 5 * time.Second
+	}
+
+	s := &webhookSink{
+		url:        cfg.URL,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		batchSize:  batchSize,
+		flushEvery: flushEvery,
+		done:       make(chan struct{}),
+	}
+
+	go s.flushLoop()
+
+	return s
+}
+
+func (s *webhookSink) Write(event Event) error {
+	s.mu.Lock()
+	s.pending = append(s.pending, event)
+	shouldFlush := len(s.pending) >= s.batchSize
+	s.mu.Unlock()
+
+	if shouldFlush {
+		return s.flush()
+	}
+	return nil
+}
+
+func (s *webhookSink) flushLoop() {
+	ticker := time.NewTicker(s.flushEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			// TODO: log flush errors once a logger is threaded through
+			_ = s.flush()
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// Close stops the background flush loop and flushes any events still
+// pending, so a graceful shutdown doesn't silently drop them.
+func (s *webhookSink) Close() error {
+	close(s.done)
+	return s.flush()
+}
+
+func (s *webhookSink) flush() error {
+	s.mu.Lock()
+	batch := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(map[string]any{"items": batch})
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.client.Post(s.url, "application/json", bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("audit: webhook sink received status %d", resp.StatusCode)
+	}
+	return nil
+}
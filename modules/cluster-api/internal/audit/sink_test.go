@@ -0,0 +1,73 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+)
+
+func TestWebhookSinkCloseFlushesPendingEvents(t *testing.T) {
+	var mu sync.Mutex
+	var received []map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload struct {
+			Items []map[string]any `json:"items"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&payload)
+
+		mu.Lock()
+		received = append(received, payload.Items...)
+		mu.Unlock()
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(config.AuditWebhookSinkConfig{
+		URL:                  server.URL,
+		BatchSize:            100, // large enough that Write alone won't trigger a flush
+		FlushIntervalSeconds: 3600,
+	})
+
+	if err := sink.Write(Event{OperationName: "Logs"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 1 {
+		t.Fatalf("server received %d events after Close, want 1 (pending events must be flushed on shutdown)", len(received))
+	}
+
+	// Close must close the done channel the flush loop selects on, so the
+	// loop stops instead of being left running.
+	select {
+	case <-sink.done:
+	case <-time.After(time.Second):
+		t.Fatal("Close did not close the done channel that stops the flush loop")
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth provides pluggable authentication providers for the
+// cluster-api service. A Provider validates incoming requests and
+// resolves them to an Identity that downstream GraphQL resolvers can use
+// to scope their Kubernetes API access.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+)
+
+// ErrUnauthenticated is returned by a Provider when a request does not
+// carry valid credentials.
+var ErrUnauthenticated = errors.New("auth: request is not authenticated")
+
+// Identity represents the authenticated caller a request was resolved to.
+type Identity struct {
+	// Username is the Kubernetes user to impersonate, e.g. a subject claim
+	// or a certificate SAN.
+	Username string
+
+	// Groups are the Kubernetes groups to impersonate, if any.
+	Groups []string
+
+	// Provider is the name of the Provider that produced this Identity.
+	Provider string
+
+	// Extra carries provider-specific claims (e.g. raw JWT claims) that
+	// callers may want for logging or authorization decisions.
+	Extra map[string]string
+}
+
+// Provider authenticates incoming HTTP requests and exposes a gin
+// middleware that stores the resolved Identity on the request context.
+type Provider interface {
+	// Authenticate validates the request's credentials and returns the
+	// resolved Identity, or ErrUnauthenticated if none are present or
+	// valid.
+	Authenticate(r *http.Request) (Identity, error)
+
+	// Middleware returns a gin.HandlerFunc that calls Authenticate and
+	// aborts the request with 401 on failure, storing the Identity on
+	// the request context (see WithIdentity) on success.
+	Middleware() gin.HandlerFunc
+}
+
+// identityContextKey is the context key used to store an Identity on a
+// request context.
+type identityContextKey struct{}
+
+// IdentityFromContext returns the Identity stored on ctx, if any.
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityContextKey{}).(Identity)
+	return identity, ok
+}
+
+// WithIdentity returns a copy of ctx with identity attached.
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityContextKey{}, identity)
+}
+
+// NewProvider constructs the Provider configured via cfg.ClusterAPI.Auth.
+// It returns nil when auth is disabled, in which case callers should fall
+// back to the existing single-tenant authentication behavior.
+func NewProvider(cfg *config.Config) (Provider, error) {
+	authCfg := cfg.ClusterAPI.Auth
+
+	if !authCfg.Enabled {
+		return nil, nil
+	}
+
+	switch authCfg.Provider {
+	case "oidc":
+		return NewOIDCProvider(authCfg.OIDC)
+	case "jwt":
+		return NewJWTProvider(authCfg.JWT)
+	case "mtls":
+		return NewMTLSProvider(authCfg.MTLS)
+	default:
+		return nil, fmt.Errorf("auth: unknown provider %q", authCfg.Provider)
+	}
+}
+
+// abortUnauthorized writes a 401 response and aborts the gin context.
+func abortUnauthorized(c *gin.Context, err error) {
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"message": err.Error()})
+}
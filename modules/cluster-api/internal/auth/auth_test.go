@@ -0,0 +1,102 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+)
+
+func TestNewProviderDisabledReturnsNil(t *testing.T) {
+	cfg := &config.Config{}
+
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		t.Fatalf("NewProvider: unexpected error: %v", err)
+	}
+	if provider != nil {
+		t.Fatalf("NewProvider: got %T, want nil when auth is disabled", provider)
+	}
+}
+
+func TestNewProviderUnknownProviderErrors(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.ClusterAPI.Auth.Enabled = true
+	cfg.ClusterAPI.Auth.Provider = "bogus"
+
+	if _, err := NewProvider(cfg); err == nil {
+		t.Fatal("NewProvider: expected error for an unknown provider name")
+	}
+}
+
+func TestNewProviderDispatchesToJWT(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.ClusterAPI.Auth.Enabled = true
+	cfg.ClusterAPI.Auth.Provider = "jwt"
+	cfg.ClusterAPI.Auth.JWT.HMACSecret = "test-secret"
+
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	if _, ok := provider.(*JWTProvider); !ok {
+		t.Fatalf("NewProvider: got %T, want *JWTProvider", provider)
+	}
+}
+
+func TestNewProviderDispatchesToMTLS(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.ClusterAPI.Auth.Enabled = true
+	cfg.ClusterAPI.Auth.Provider = "mtls"
+
+	provider, err := NewProvider(cfg)
+	if err != nil {
+		t.Fatalf("NewProvider: %v", err)
+	}
+	if _, ok := provider.(*MTLSProvider); !ok {
+		t.Fatalf("NewProvider: got %T, want *MTLSProvider", provider)
+	}
+}
+
+func TestNewProviderDispatchesToOIDCPropagatesValidationError(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.ClusterAPI.Auth.Enabled = true
+	cfg.ClusterAPI.Auth.Provider = "oidc"
+	// Leaving OIDC unconfigured should surface NewOIDCProvider's own
+	// fail-fast validation error rather than reaching the network.
+	if _, err := NewProvider(cfg); err == nil {
+		t.Fatal("NewProvider: expected error for an unconfigured oidc provider")
+	}
+}
+
+func TestIdentityContextRoundTrip(t *testing.T) {
+	identity := Identity{Username: "alice", Groups: []string{"team-a"}, Provider: "jwt"}
+
+	ctx := WithIdentity(context.Background(), identity)
+
+	got, ok := IdentityFromContext(ctx)
+	if !ok {
+		t.Fatal("IdentityFromContext: expected an identity to be present")
+	}
+	if got.Username != identity.Username || len(got.Groups) != len(identity.Groups) {
+		t.Errorf("IdentityFromContext = %+v, want %+v", got, identity)
+	}
+
+	if _, ok := IdentityFromContext(context.Background()); ok {
+		t.Error("IdentityFromContext: expected no identity on a bare context")
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+func TestImpersonatedConfigSetsUserAndGroups(t *testing.T) {
+	base := &rest.Config{Host: "https://kubernetes.example.invalid"}
+	identity := Identity{Username: "alice@example.com", Groups: []string{"team-a", "team-b"}}
+
+	cfg := impersonatedConfig(base, identity)
+
+	if cfg.Impersonate.UserName != identity.Username {
+		t.Errorf("Impersonate.UserName = %q, want %q", cfg.Impersonate.UserName, identity.Username)
+	}
+	if len(cfg.Impersonate.Groups) != 2 || cfg.Impersonate.Groups[0] != "team-a" {
+		t.Errorf("Impersonate.Groups = %v, want [team-a team-b]", cfg.Impersonate.Groups)
+	}
+	if cfg.Host != base.Host {
+		t.Errorf("Host = %q, want %q (impersonation must not change the target cluster)", cfg.Host, base.Host)
+	}
+
+	// The returned config must be a copy: mutating it must not affect base,
+	// since ClientFactory.base is reused across every identity.
+	if base.Impersonate.UserName != "" {
+		t.Error("impersonatedConfig mutated the shared base config")
+	}
+}
+
+func TestForIdentityBuildsAClientPerIdentity(t *testing.T) {
+	factory := &ClientFactory{base: &rest.Config{Host: "https://kubernetes.example.invalid"}}
+
+	client, err := factory.ForIdentity(Identity{Username: "alice@example.com"})
+	if err != nil {
+		t.Fatalf("ForIdentity: %v", err)
+	}
+	if client == nil {
+		t.Fatal("ForIdentity: got nil client")
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+)
+
+func signHMAC(t *testing.T, secret string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("failed to sign test token: %v", err)
+	}
+	return signed
+}
+
+func TestJWTProviderAuthenticate(t *testing.T) {
+	provider, err := NewJWTProvider(config.JWTAuthConfig{HMACSecret: "test-secret"})
+	if err != nil {
+		t.Fatalf("NewJWTProvider: %v", err)
+	}
+
+	rawToken := signHMAC(t, "test-secret", jwt.MapClaims{
+		"sub":    "alice",
+		"groups": []any{"team-a", "team-b"},
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", nil)
+	req.Header.Set("Authorization", "Bearer "+rawToken)
+
+	identity, err := provider.Authenticate(req)
+	if err != nil {
+		t.Fatalf("Authenticate: unexpected error: %v", err)
+	}
+	if identity.Username != "alice" {
+		t.Errorf("Username = %q, want %q", identity.Username, "alice")
+	}
+	if len(identity.Groups) != 2 || identity.Groups[0] != "team-a" {
+		t.Errorf("Groups = %v, want [team-a team-b]", identity.Groups)
+	}
+}
+
+func TestJWTProviderAuthenticateRejectsWrongKey(t *testing.T) {
+	provider, err := NewJWTProvider(config.JWTAuthConfig{HMACSecret: "test-secret"})
+	if err != nil {
+		t.Fatalf("NewJWTProvider: %v", err)
+	}
+
+	rawToken := signHMAC(t, "wrong-secret", jwt.MapClaims{"sub": "alice"})
+
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", nil)
+	req.Header.Set("Authorization", "Bearer "+rawToken)
+
+	if _, err := provider.Authenticate(req); err == nil {
+		t.Fatal("Authenticate: expected error for token signed with the wrong key")
+	}
+}
+
+func TestJWTProviderAuthenticateRejectsMissingHeader(t *testing.T) {
+	provider, err := NewJWTProvider(config.JWTAuthConfig{HMACSecret: "test-secret"})
+	if err != nil {
+		t.Fatalf("NewJWTProvider: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", nil)
+
+	if _, err := provider.Authenticate(req); err != ErrUnauthenticated {
+		t.Fatalf("Authenticate: got %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestNewJWTProviderRequiresKey(t *testing.T) {
+	if _, err := NewJWTProvider(config.JWTAuthConfig{}); err == nil {
+		t.Fatal("NewJWTProvider: expected error when neither hmac secret nor rsa key is configured")
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+)
+
+// MTLSProvider resolves an Identity from the SANs of the client
+// certificate presented during the TLS handshake, for deployments that
+// terminate mTLS at the cluster-api itself (as opposed to a front proxy).
+// The resolved Identity is stored on the request context; the
+// per-identity Kubernetes client that actually impersonates it is built
+// by auth.ClientFactory using rest.Config.Impersonate, not by forwarding
+// a header.
+type MTLSProvider struct {
+	usernameSANType string
+	groupsSANType   string
+}
+
+// NewMTLSProvider creates an MTLSProvider from the given config.
+func NewMTLSProvider(cfg config.MTLSAuthConfig) (*MTLSProvider, error) {
+	usernameSANType := cfg.UsernameSANType
+	if usernameSANType == "" {
+		usernameSANType = "dns"
+	}
+
+	groupsSANType := cfg.GroupsSANType
+	if groupsSANType == "" {
+		groupsSANType = "uri"
+	}
+
+	return &MTLSProvider{
+		usernameSANType: usernameSANType,
+		groupsSANType:   groupsSANType,
+	}, nil
+}
+
+// Authenticate implements Provider.
+func (p *MTLSProvider) Authenticate(r *http.Request) (Identity, error) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return Identity{}, ErrUnauthenticated
+	}
+
+	cert := r.TLS.PeerCertificates[0]
+
+	username := sansByType(cert, p.usernameSANType)
+	if username == "" {
+		return Identity{}, fmt.Errorf("%w: client certificate has no usable %s SAN", ErrUnauthenticated, p.usernameSANType)
+	}
+
+	return Identity{
+		Username: username,
+		Groups:   sansListByType(cert, p.groupsSANType),
+		Provider: "mtls",
+	}, nil
+}
+
+// Middleware implements Provider.
+func (p *MTLSProvider) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, err := p.Authenticate(c.Request)
+		if err != nil {
+			abortUnauthorized(c, err)
+			return
+		}
+
+		c.Request = c.Request.WithContext(WithIdentity(c.Request.Context(), identity))
+		c.Next()
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+)
+
+func TestMTLSProviderAuthenticate(t *testing.T) {
+	provider, err := NewMTLSProvider(config.MTLSAuthConfig{})
+	if err != nil {
+		t.Fatalf("NewMTLSProvider: %v", err)
+	}
+
+	cert := &x509.Certificate{
+		DNSNames: []string{"alice.clients.example.com"},
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", nil)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+
+	identity, err := provider.Authenticate(req)
+	if err != nil {
+		t.Fatalf("Authenticate: unexpected error: %v", err)
+	}
+	if identity.Username != "alice.clients.example.com" {
+		t.Errorf("Username = %q, want the cert's DNS SAN", identity.Username)
+	}
+}
+
+func TestMTLSProviderAuthenticateRejectsMissingCert(t *testing.T) {
+	provider, err := NewMTLSProvider(config.MTLSAuthConfig{})
+	if err != nil {
+		t.Fatalf("NewMTLSProvider: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", nil)
+
+	if _, err := provider.Authenticate(req); err != ErrUnauthenticated {
+		t.Fatalf("Authenticate: got %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestMTLSProviderMiddlewareDoesNotSetImpersonationHeaders(t *testing.T) {
+	// Regression test: impersonation must happen via rest.Config.Impersonate
+	// in auth.ClientFactory, not by setting headers on the inbound request
+	// (which never reach the outbound Kubernetes API call).
+	gin.SetMode(gin.TestMode)
+
+	provider, err := NewMTLSProvider(config.MTLSAuthConfig{})
+	if err != nil {
+		t.Fatalf("NewMTLSProvider: %v", err)
+	}
+
+	cert := &x509.Certificate{DNSNames: []string{"alice.clients.example.com"}}
+
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", nil)
+	req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	provider.Middleware()(c)
+
+	if got := c.Request.Header.Get("Impersonate-User"); got != "" {
+		t.Fatalf("Impersonate-User header = %q, want empty (impersonation happens via ClientFactory)", got)
+	}
+
+	identity, ok := IdentityFromContext(c.Request.Context())
+	if !ok {
+		t.Fatal("expected identity to be stored on the request context")
+	}
+	if identity.Username != "alice.clients.example.com" {
+		t.Errorf("Username = %q, want the cert's DNS SAN", identity.Username)
+	}
+}
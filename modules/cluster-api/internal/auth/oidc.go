@@ -0,0 +1,147 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/gin-gonic/gin"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+)
+
+// OIDCProvider authenticates bearer tokens against an OIDC provider's
+// discovery document, caching the provider's JWKS for signature
+// verification.
+type OIDCProvider struct {
+	verifier      *oidc.IDTokenVerifier
+	usernameClaim string
+	groupsClaim   string
+}
+
+// NewOIDCProvider creates an OIDCProvider from the given config, fetching
+// the issuer's discovery document up front so that misconfigured issuers
+// fail fast at startup rather than on the first request. The JWKS itself
+// is fetched and cached by the verifier when the first token is verified.
+func NewOIDCProvider(cfg config.OIDCAuthConfig) (*OIDCProvider, error) {
+	if cfg.IssuerURL == "" {
+		return nil, fmt.Errorf("auth: oidc issuer url is required")
+	}
+
+	if cfg.ClientID == "" {
+		return nil, fmt.Errorf("auth: oidc client id is required")
+	}
+
+	provider, err := oidc.NewProvider(context.Background(), cfg.IssuerURL)
+	if err != nil {
+		return nil, fmt.Errorf("auth: failed to fetch oidc discovery document: %w", err)
+	}
+
+	verifier := provider.Verifier(&oidc.Config{ClientID: cfg.ClientID})
+
+	usernameClaim := cfg.UsernameClaim
+	if usernameClaim == "" {
+		usernameClaim = "email"
+	}
+
+	groupsClaim := cfg.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+
+	return &OIDCProvider{
+		verifier:      verifier,
+		usernameClaim: usernameClaim,
+		groupsClaim:   groupsClaim,
+	}, nil
+}
+
+// Authenticate implements Provider.
+func (p *OIDCProvider) Authenticate(r *http.Request) (Identity, error) {
+	rawToken, ok := bearerToken(r)
+	if !ok {
+		return Identity{}, ErrUnauthenticated
+	}
+
+	idToken, err := p.verifier.Verify(r.Context(), rawToken)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return Identity{}, fmt.Errorf("%w: %v", ErrUnauthenticated, err)
+	}
+
+	username, _ := claims[p.usernameClaim].(string)
+	if username == "" {
+		return Identity{}, fmt.Errorf("%w: token is missing claim %q", ErrUnauthenticated, p.usernameClaim)
+	}
+
+	return Identity{
+		Username: username,
+		Groups:   stringSliceClaim(claims[p.groupsClaim]),
+		Provider: "oidc",
+	}, nil
+}
+
+// Middleware implements Provider.
+func (p *OIDCProvider) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, err := p.Authenticate(c.Request)
+		if err != nil {
+			abortUnauthorized(c, err)
+			return
+		}
+
+		c.Request = c.Request.WithContext(WithIdentity(c.Request.Context(), identity))
+		c.Next()
+	}
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer <token>" header.
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(header, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(header, prefix), true
+}
+
+// stringSliceClaim normalizes a JWT claim that may be a single string or a
+// list of strings into a []string.
+func stringSliceClaim(v any) []string {
+	switch val := v.(type) {
+	case []string:
+		return val
+	case []any:
+		out := make([]string, 0, len(val))
+		for _, item := range val {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	case string:
+		return []string{val}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+)
+
+// newTestOIDCServer serves a minimal discovery document and JWKS backed by
+// key, so NewOIDCProvider's verifier can validate tokens signed with key
+// without reaching a real identity provider.
+func newTestOIDCServer(t *testing.T, key *rsa.PrivateKey) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":   server.URL,
+			"jwks_uri": server.URL + "/keys",
+		})
+	})
+
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]any{
+				{
+					"kty": "RSA",
+					"kid": "test-key",
+					"use": "sig",
+					"alg": "RS256",
+					"n":   base64.RawURLEncoding.EncodeToString(key.PublicKey.N.Bytes()),
+					"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.PublicKey.E)).Bytes()),
+				},
+			},
+		})
+	})
+
+	return server
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test-key"
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatalf("failed to sign test id token: %v", err)
+	}
+	return signed
+}
+
+func TestNewOIDCProviderRequiresIssuerURL(t *testing.T) {
+	if _, err := NewOIDCProvider(config.OIDCAuthConfig{ClientID: "cluster-api"}); err == nil {
+		t.Fatal("NewOIDCProvider: expected error when issuer url is missing")
+	}
+}
+
+func TestNewOIDCProviderRequiresClientID(t *testing.T) {
+	if _, err := NewOIDCProvider(config.OIDCAuthConfig{IssuerURL: "https://example.com"}); err == nil {
+		t.Fatal("NewOIDCProvider: expected error when client id is missing")
+	}
+}
+
+func TestOIDCProviderAuthenticate(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	server := newTestOIDCServer(t, key)
+
+	provider, err := NewOIDCProvider(config.OIDCAuthConfig{
+		IssuerURL: server.URL,
+		ClientID:  "cluster-api",
+	})
+	if err != nil {
+		t.Fatalf("NewOIDCProvider: %v", err)
+	}
+
+	rawToken := signRS256(t, key, jwt.MapClaims{
+		"iss":    server.URL,
+		"aud":    "cluster-api",
+		"sub":    "user-id-irrelevant-since-username-claim-is-email",
+		"email":  "alice@example.com",
+		"groups": []any{"team-a", "team-b"},
+		"iat":    time.Now().Unix(),
+		"exp":    time.Now().Add(time.Hour).Unix(),
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", nil)
+	req.Header.Set("Authorization", "Bearer "+rawToken)
+
+	identity, err := provider.Authenticate(req)
+	if err != nil {
+		t.Fatalf("Authenticate: unexpected error: %v", err)
+	}
+	if identity.Username != "alice@example.com" {
+		t.Errorf("Username = %q, want %q", identity.Username, "alice@example.com")
+	}
+	if len(identity.Groups) != 2 || identity.Groups[0] != "team-a" {
+		t.Errorf("Groups = %v, want [team-a team-b]", identity.Groups)
+	}
+}
+
+func TestOIDCProviderAuthenticateRejectsMissingHeader(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	server := newTestOIDCServer(t, key)
+
+	provider, err := NewOIDCProvider(config.OIDCAuthConfig{IssuerURL: server.URL, ClientID: "cluster-api"})
+	if err != nil {
+		t.Fatalf("NewOIDCProvider: %v", err)
+	}
+
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", nil)
+	if _, err := provider.Authenticate(req); err != ErrUnauthenticated {
+		t.Fatalf("Authenticate: got %v, want ErrUnauthenticated", err)
+	}
+}
+
+func TestOIDCProviderAuthenticateRejectsMissingUsernameClaim(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("rsa.GenerateKey: %v", err)
+	}
+	server := newTestOIDCServer(t, key)
+
+	provider, err := NewOIDCProvider(config.OIDCAuthConfig{IssuerURL: server.URL, ClientID: "cluster-api"})
+	if err != nil {
+		t.Fatalf("NewOIDCProvider: %v", err)
+	}
+
+	rawToken := signRS256(t, key, jwt.MapClaims{
+		"iss": server.URL,
+		"aud": "cluster-api",
+		"sub": "alice",
+		"iat": time.Now().Unix(),
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/graphql", nil)
+	req.Header.Set("Authorization", "Bearer "+rawToken)
+
+	if _, err := provider.Authenticate(req); err == nil {
+		t.Fatal("Authenticate: expected error when the username claim (email) is absent")
+	}
+}
+
+func TestStringSliceClaim(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want []string
+	}{
+		{"nil", nil, nil},
+		{"single string", "team-a", []string{"team-a"}},
+		{"string slice", []string{"team-a", "team-b"}, []string{"team-a", "team-b"}},
+		{"any slice of strings", []any{"team-a", "team-b"}, []string{"team-a", "team-b"}},
+		{"any slice drops non-strings", []any{"team-a", 1}, []string{"team-a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := stringSliceClaim(tt.in)
+			if len(got) != len(tt.want) {
+				t.Fatalf("stringSliceClaim(%v) = %v, want %v", tt.in, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("stringSliceClaim(%v)[%d] = %q, want %q", tt.in, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import "crypto/x509"
+
+// sansByType returns the first SAN of the given type ("dns", "email" or
+// "uri") from cert, or "" if none is present.
+func sansByType(cert *x509.Certificate, sanType string) string {
+	sans := sansListByType(cert, sanType)
+	if len(sans) == 0 {
+		return ""
+	}
+	return sans[0]
+}
+
+// sansListByType returns all SANs of the given type from cert.
+func sansListByType(cert *x509.Certificate, sanType string) []string {
+	switch sanType {
+	case "dns":
+		return cert.DNSNames
+	case "email":
+		return cert.EmailAddresses
+	case "uri":
+		uris := make([]string, 0, len(cert.URIs))
+		for _, u := range cert.URIs {
+			uris = append(uris, u.String())
+		}
+		return uris
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cors builds a gin-contrib/cors configuration from
+// cfg.ClusterAPI.CORS, validating the configured origins up front so that
+// a typo'd allow-list fails at startup rather than silently rejecting
+// every browser request.
+//
+// Wildcard origins (e.g. "https://*.example.com") and regex origins
+// (slash-delimited, e.g. "/^https://.*\\.example\\.com$/") use
+// gin-contrib/cors's own matching conventions rather than reimplementing
+// them here, since the library already validates and matches both
+// correctly (AllowWildcard + its glob splitter for the former, its
+// validateOrigin/regex handling for the latter).
+package cors
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gin-contrib/cors"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+)
+
+// BuildConfig validates cfg and returns the cors.Config to register on
+// the dynamic routes group.
+func BuildConfig(cfg config.CORSConfig) (cors.Config, error) {
+	if len(cfg.AllowOrigins) == 0 {
+		return cors.Config{}, fmt.Errorf("cors: at least one allowed origin is required")
+	}
+
+	out := cors.Config{
+		AllowOrigins:     cfg.AllowOrigins,
+		AllowMethods:     cfg.AllowMethods,
+		AllowHeaders:     cfg.AllowHeaders,
+		ExposeHeaders:    cfg.ExposeHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           time.Duration(cfg.MaxAge) * time.Second,
+		AllowWildcard:    true,
+	}
+
+	for _, origin := range cfg.AllowOrigins {
+		if origin == "*" {
+			out.AllowAllOrigins = true
+		}
+	}
+
+	if out.AllowAllOrigins && out.AllowCredentials {
+		return cors.Config{}, fmt.Errorf("cors: allowCredentials cannot be combined with a wildcard origin")
+	}
+
+	// Fail fast on a malformed glob or an unparsable /regex/ entry instead
+	// of discovering it on the first browser preflight.
+	if err := out.Validate(); err != nil {
+		return cors.Config{}, fmt.Errorf("cors: invalid configuration: %w", err)
+	}
+
+	return out, nil
+}
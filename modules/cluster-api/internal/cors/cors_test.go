@@ -0,0 +1,84 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+
+	"github.com/kubetail-org/kubetail/modules/shared/config"
+)
+
+func TestBuildConfigRequiresAnOrigin(t *testing.T) {
+	if _, err := BuildConfig(config.CORSConfig{}); err == nil {
+		t.Fatal("BuildConfig: expected error when no origins are configured")
+	}
+}
+
+func TestBuildConfigRejectsCredentialsWithWildcardAll(t *testing.T) {
+	_, err := BuildConfig(config.CORSConfig{
+		AllowOrigins:     []string{"*"},
+		AllowCredentials: true,
+	})
+	if err == nil {
+		t.Fatal("BuildConfig: expected error combining AllowCredentials with \"*\"")
+	}
+}
+
+// preflight sends an OPTIONS request with the given Origin through cfg's
+// CORS middleware and returns the resulting Access-Control-Allow-Origin
+// header (empty if the origin was rejected).
+func preflight(t *testing.T, cfg cors.Config, origin string) string {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	engine.Use(cors.New(cfg))
+	engine.GET("/graphql", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodOptions, "/graphql", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	return w.Header().Get("Access-Control-Allow-Origin")
+}
+
+func TestBuildConfigSubdomainWildcardMatches(t *testing.T) {
+	cfg, err := BuildConfig(config.CORSConfig{
+		AllowOrigins: []string{"https://*.example.com"},
+	})
+	if err != nil {
+		t.Fatalf("BuildConfig: unexpected error: %v", err)
+	}
+
+	for _, origin := range []string{"https://foo.example.com", "https://app.example.com"} {
+		if got := preflight(t, cfg, origin); got != origin {
+			t.Errorf("preflight(%q) allow-origin = %q, want %q", origin, got, origin)
+		}
+	}
+
+	for _, origin := range []string{"https://example.com", "https://evil.com"} {
+		if got := preflight(t, cfg, origin); got != "" {
+			t.Errorf("preflight(%q) allow-origin = %q, want rejected (empty)", origin, got)
+		}
+	}
+}
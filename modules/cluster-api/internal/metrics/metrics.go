@@ -0,0 +1,193 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package metrics holds the Prometheus collectors for the cluster-api
+// service and the gin middleware that records them per-route.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// GRPCDispatcherStats is the subset of grpcdispatcher.Dispatcher's
+// connection bookkeeping the Registry needs to keep its gauges current.
+type GRPCDispatcherStats interface {
+	ConnectedCount() int
+	FailedCount() int
+}
+
+// Registry holds the collectors registered by the cluster-api so that
+// the gRPC dispatcher and GraphQL server can register their own gauges
+// against it without reaching for the global prometheus.DefaultRegisterer.
+type Registry struct {
+	registerer prometheus.Registerer
+	gatherer   prometheus.Gatherer
+
+	RequestsTotal    *prometheus.CounterVec
+	RequestsInFlight *prometheus.GaugeVec
+	RequestDuration  *prometheus.HistogramVec
+
+	GraphQLSubscriptionsActive      prometheus.Gauge
+	GRPCDispatcherConnections       prometheus.Gauge
+	GRPCDispatcherFailedConnections prometheus.Gauge
+}
+
+// NewRegistry creates a Registry with a private prometheus.Registry so
+// that multiple App instances (e.g. in tests) don't collide on metric
+// registration.
+func NewRegistry() *Registry {
+	reg := prometheus.NewRegistry()
+
+	return &Registry{
+		registerer: reg,
+		gatherer:   reg,
+
+		RequestsTotal: promauto.With(reg).NewCounterVec(prometheus.CounterOpts{
+			Namespace: "kubetail",
+			Subsystem: "cluster_api",
+			Name:      "http_requests_total",
+			Help:      "Total number of HTTP requests processed, labeled by route and status code.",
+		}, []string{"route", "method", "status"}),
+
+		RequestsInFlight: promauto.With(reg).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "kubetail",
+			Subsystem: "cluster_api",
+			Name:      "http_requests_in_flight",
+			Help:      "Number of HTTP requests currently being processed, labeled by route.",
+		}, []string{"route"}),
+
+		RequestDuration: promauto.With(reg).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "kubetail",
+			Subsystem: "cluster_api",
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of HTTP requests, labeled by route, method and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+
+		GraphQLSubscriptionsActive: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: "kubetail",
+			Subsystem: "cluster_api",
+			Name:      "graphql_subscriptions_active",
+			Help:      "Number of currently active GraphQL subscriptions.",
+		}),
+
+		GRPCDispatcherConnections: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: "kubetail",
+			Subsystem: "cluster_api",
+			Name:      "grpc_dispatcher_connections",
+			Help:      "Number of node agents currently connected to the gRPC dispatcher.",
+		}),
+
+		GRPCDispatcherFailedConnections: promauto.With(reg).NewGauge(prometheus.GaugeOpts{
+			Namespace: "kubetail",
+			Subsystem: "cluster_api",
+			Name:      "grpc_dispatcher_failed_connections",
+			Help:      "Number of node agents the gRPC dispatcher has failed to connect to.",
+		}),
+	}
+}
+
+// WatchGRPCDispatcher starts a goroutine that polls dispatcher's
+// connection counts every interval and keeps GRPCDispatcherConnections /
+// GRPCDispatcherFailedConnections current, so operators can alert on
+// log-streaming backpressure without scraping logs. The goroutine exits
+// when stop is closed.
+func (r *Registry) WatchGRPCDispatcher(dispatcher GRPCDispatcherStats, interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			r.GRPCDispatcherConnections.Set(float64(dispatcher.ConnectedCount()))
+			r.GRPCDispatcherFailedConnections.Set(float64(dispatcher.FailedCount()))
+
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// GraphQLSubscriptionMiddleware tracks GraphQLSubscriptionsActive for
+// requests that upgrade to a long-lived subscription transport (e.g. a
+// graphql-ws/graphql-transport-ws WebSocket), identified by the
+// Connection: Upgrade header a subscription handshake carries. Register
+// it ahead of the GraphQL endpoint specifically, not globally, since
+// regular queries/mutations aren't subscriptions.
+func (r *Registry) GraphQLSubscriptionMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !isUpgradeRequest(c.Request) {
+			c.Next()
+			return
+		}
+
+		r.GraphQLSubscriptionsActive.Inc()
+		defer r.GraphQLSubscriptionsActive.Dec()
+
+		c.Next()
+	}
+}
+
+func isUpgradeRequest(r *http.Request) bool {
+	for _, token := range strings.Split(r.Header.Get("Connection"), ",") {
+		if strings.EqualFold(strings.TrimSpace(token), "Upgrade") {
+			return true
+		}
+	}
+	return false
+}
+
+// Registerer exposes the underlying prometheus.Registerer so that other
+// subsystems (the gRPC dispatcher, the GraphQL server) can register
+// their own collectors against the same registry.
+func (r *Registry) Registerer() prometheus.Registerer {
+	return r.registerer
+}
+
+// Gatherer exposes the underlying prometheus.Gatherer for the /metrics handler.
+func (r *Registry) Gatherer() prometheus.Gatherer {
+	return r.gatherer
+}
+
+// Middleware returns a gin.HandlerFunc that records request count,
+// in-flight gauge and latency histogram for every route it's installed on.
+func (r *Registry) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		r.RequestsInFlight.WithLabelValues(route).Inc()
+		defer r.RequestsInFlight.WithLabelValues(route).Dec()
+
+		start := time.Now()
+		c.Next()
+		duration := time.Since(start).Seconds()
+
+		status := strconv.Itoa(c.Writer.Status())
+		r.RequestsTotal.WithLabelValues(route, c.Request.Method, status).Inc()
+		r.RequestDuration.WithLabelValues(route, c.Request.Method, status).Observe(duration)
+	}
+}
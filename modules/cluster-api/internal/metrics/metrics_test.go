@@ -0,0 +1,98 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// fakeDispatcherStats is a stand-in for grpcdispatcher.Dispatcher.
+type fakeDispatcherStats struct {
+	connected int
+	failed    int
+}
+
+func (f *fakeDispatcherStats) ConnectedCount() int { return f.connected }
+func (f *fakeDispatcherStats) FailedCount() int     { return f.failed }
+
+func TestWatchGRPCDispatcherUpdatesGauges(t *testing.T) {
+	r := NewRegistry()
+	stats := &fakeDispatcherStats{connected: 3, failed: 1}
+	stop := make(chan struct{})
+	defer close(stop)
+
+	r.WatchGRPCDispatcher(stats, time.Hour, stop)
+
+	// The first poll happens immediately rather than after the first
+	// interval tick, but it runs in its own goroutine, so give it a moment
+	// to land.
+	deadline := time.After(time.Second)
+	for {
+		if testutil.ToFloat64(r.GRPCDispatcherConnections) == 3 && testutil.ToFloat64(r.GRPCDispatcherFailedConnections) == 1 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("gauges were not updated: connections=%v failed=%v",
+				testutil.ToFloat64(r.GRPCDispatcherConnections), testutil.ToFloat64(r.GRPCDispatcherFailedConnections))
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestWatchGRPCDispatcherStopsOnClose(t *testing.T) {
+	r := NewRegistry()
+	stats := &fakeDispatcherStats{connected: 1}
+	stop := make(chan struct{})
+
+	r.WatchGRPCDispatcher(stats, time.Millisecond, stop)
+	close(stop)
+
+	// Give the goroutine time to observe the close and exit; there's no
+	// direct way to assert a goroutine has stopped, so this just guards
+	// against a panic/race from writing to a gauge after the test ends.
+	time.Sleep(10 * time.Millisecond)
+}
+
+func TestIsUpgradeRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"upgrade", "Upgrade", true},
+		{"upgrade with keep-alive", "keep-alive, Upgrade", true},
+		{"case insensitive", "upgrade", true},
+		{"absent", "", false},
+		{"keep-alive only", "keep-alive", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+			if tt.header != "" {
+				r.Header.Set("Connection", tt.header)
+			}
+			if got := isUpgradeRequest(r); got != tt.want {
+				t.Errorf("isUpgradeRequest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
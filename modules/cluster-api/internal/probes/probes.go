@@ -0,0 +1,96 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package probes implements Kubernetes-style liveness and readiness
+// checks for the cluster-api service, modeled on kube-apiserver's own
+// /livez and /readyz semantics (including the verbose=1 query param).
+package probes
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Check is a single named dependency check. It returns a nil error when
+// the dependency is healthy.
+type Check struct {
+	Name string
+	Func func(ctx context.Context) error
+}
+
+// LivezHandler always reports ok once the process is able to serve
+// requests; it does not exercise any external dependency.
+func LivezHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	}
+}
+
+// ReadyzHandler runs checks and returns 200 when all of them pass, or 503
+// with a JSON body listing the failing subsystems otherwise. When the
+// request carries `?verbose=1` the response lists every check's status,
+// mirroring `kubectl get --raw /readyz?verbose` against kube-apiserver.
+func ReadyzHandler(checks []Check) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := c.Request.Context()
+
+		type result struct {
+			name string
+			err  error
+		}
+
+		results := make([]result, len(checks))
+		failed := make([]string, 0)
+
+		for i, check := range checks {
+			err := check.Func(ctx)
+			results[i] = result{name: check.Name, err: err}
+			if err != nil {
+				failed = append(failed, check.Name)
+			}
+		}
+
+		status := http.StatusOK
+		if len(failed) > 0 {
+			status = http.StatusServiceUnavailable
+		}
+
+		if c.Query("verbose") == "1" {
+			checksOut := make(gin.H, len(results))
+			for _, r := range results {
+				if r.err != nil {
+					checksOut[r.name] = "failed: " + r.err.Error()
+				} else {
+					checksOut[r.name] = "ok"
+				}
+			}
+
+			body := gin.H{"checks": checksOut}
+			if len(failed) > 0 {
+				body["failedChecks"] = failed
+			}
+			c.JSON(status, body)
+			return
+		}
+
+		if len(failed) > 0 {
+			c.JSON(status, gin.H{"failedChecks": failed})
+			return
+		}
+
+		c.JSON(status, gin.H{"status": "ok"})
+	}
+}
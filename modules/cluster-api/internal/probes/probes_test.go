@@ -0,0 +1,118 @@
+// Copyright 2024-2025 Andres Morey
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probes
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func performReadyz(checks []Check, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", target, nil)
+
+	ReadyzHandler(checks)(c)
+	return w
+}
+
+func TestLivezHandlerAlwaysOK(t *testing.T) {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/livez", nil)
+
+	LivezHandler()(c)
+
+	if w.Code != 200 {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestReadyzHandlerAllPass(t *testing.T) {
+	checks := []Check{
+		{Name: "a", Func: func(ctx context.Context) error { return nil }},
+		{Name: "b", Func: func(ctx context.Context) error { return nil }},
+	}
+
+	w := performReadyz(checks, "/readyz")
+
+	if w.Code != 200 {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+}
+
+func TestReadyzHandlerListsMultipleFailures(t *testing.T) {
+	checks := []Check{
+		{Name: "kubernetes-api", Func: func(ctx context.Context) error { return errors.New("unreachable") }},
+		{Name: "grpc-dispatcher", Func: func(ctx context.Context) error { return errors.New("no node agents connected") }},
+		{Name: "ok-check", Func: func(ctx context.Context) error { return nil }},
+	}
+
+	w := performReadyz(checks, "/readyz")
+
+	if w.Code != 503 {
+		t.Errorf("status = %d, want 503", w.Code)
+	}
+	body := w.Body.String()
+	for _, name := range []string{"kubernetes-api", "grpc-dispatcher"} {
+		if !strings.Contains(body, name) {
+			t.Errorf("body %q does not mention failing check %q", body, name)
+		}
+	}
+	if strings.Contains(body, `"ok-check"`) {
+		t.Errorf("body %q should not list the passing check in failedChecks", body)
+	}
+}
+
+func TestReadyzHandlerVerboseListsEveryCheck(t *testing.T) {
+	checks := []Check{
+		{Name: "kubernetes-api", Func: func(ctx context.Context) error { return nil }},
+		{Name: "grpc-dispatcher", Func: func(ctx context.Context) error { return errors.New("no node agents connected") }},
+	}
+
+	w := performReadyz(checks, "/readyz?verbose=1")
+
+	if w.Code != 503 {
+		t.Errorf("status = %d, want 503", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, `"kubernetes-api":"ok"`) {
+		t.Errorf("body %q should report the passing check as ok", body)
+	}
+	if !strings.Contains(body, `"grpc-dispatcher":"failed: no node agents connected"`) {
+		t.Errorf("body %q should report the failing check's error", body)
+	}
+	if !strings.Contains(body, `"failedChecks"`) {
+		t.Errorf("body %q should still list failedChecks alongside the per-check detail", body)
+	}
+}
+
+func TestReadyzHandlerNoChecksPasses(t *testing.T) {
+	w := performReadyz(nil, "/readyz")
+
+	if w.Code != 200 {
+		t.Errorf("status = %d, want 200 (no dependencies configured means nothing to fail)", w.Code)
+	}
+}
+